@@ -0,0 +1,132 @@
+package compute
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/helpers/azure"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/clients"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/tags"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/timeouts"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
+)
+
+func dataSourceArmManagedDiskSnapshot() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceArmManagedDiskSnapshotRead,
+
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"resource_group_name": azure.SchemaResourceGroupNameForDataSource(),
+
+			"location": azure.SchemaLocationForDataSource(),
+
+			"create_option": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"source_uri": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"source_resource_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"incremental": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"disk_size_gb": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"disk_encryption_set_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"source_unique_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"tags": tags.SchemaDataSource(),
+		},
+	}
+}
+
+func dataSourceArmManagedDiskSnapshotRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*clients.Client).Compute.SnapshotsClient
+	ctx, cancel := timeouts.ForRead(meta.(*clients.Client).StopContext, d)
+	defer cancel()
+
+	name := d.Get("name").(string)
+	resGroup := d.Get("resource_group_name").(string)
+
+	resp, err := client.Get(ctx, resGroup, name)
+	if err != nil {
+		if utils.ResponseWasNotFound(resp.Response) {
+			return fmt.Errorf("Managed Disk Snapshot %q (Resource Group %q) was not found", name, resGroup)
+		}
+		return fmt.Errorf("Error making Read request on Azure Managed Disk Snapshot %q (Resource Group %q): %+v", name, resGroup, err)
+	}
+
+	if resp.ID == nil {
+		return fmt.Errorf("Error reading Managed Disk Snapshot %s (Resource Group %q): ID was nil", name, resGroup)
+	}
+	d.SetId(*resp.ID)
+
+	if location := resp.Location; location != nil {
+		d.Set("location", azure.NormalizeLocation(*location))
+	}
+
+	dataSourceFlattenArmManagedDiskSnapshotProperties(d, resp.SnapshotProperties)
+
+	return tags.FlattenAndSet(d, resp.Tags)
+}
+
+// dataSourceFlattenArmManagedDiskSnapshotProperties mirrors flattenArmManagedDiskSnapshotProperties
+// but only sets the fields this data source's schema actually declares - unlike the resource, the
+// data source doesn't expose `encryption_settings`.
+func dataSourceFlattenArmManagedDiskSnapshotProperties(d *schema.ResourceData, props *compute.SnapshotProperties) {
+	if props == nil {
+		return
+	}
+
+	if creationData := props.CreationData; creationData != nil {
+		d.Set("create_option", string(creationData.CreateOption))
+		d.Set("source_resource_id", creationData.SourceResourceID)
+		d.Set("source_uri", creationData.SourceURI)
+	}
+
+	d.Set("disk_size_gb", props.DiskSizeGB)
+	d.Set("source_unique_id", props.UniqueID)
+
+	incremental := false
+	if props.Incremental != nil {
+		incremental = *props.Incremental
+	}
+	d.Set("incremental", incremental)
+
+	diskEncryptionSetId := ""
+	if props.Encryption != nil && props.Encryption.DiskEncryptionSetID != nil {
+		diskEncryptionSetId = *props.Encryption.DiskEncryptionSetID
+	}
+	d.Set("disk_encryption_set_id", diskEncryptionSetId)
+}
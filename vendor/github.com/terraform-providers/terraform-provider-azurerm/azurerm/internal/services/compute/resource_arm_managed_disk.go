@@ -1,6 +1,7 @@
 package compute
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -24,7 +25,7 @@ func resourceArmManagedDisk() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceArmManagedDiskCreateUpdate,
 		Read:   resourceArmManagedDiskRead,
-		Update: resourceArmManagedDiskCreateUpdate,
+		Update: resourceArmManagedDiskUpdate,
 		Delete: resourceArmManagedDiskDelete,
 
 		Importer: &schema.ResourceImporter{
@@ -145,6 +146,17 @@ func resourceArmManagedDisk() *schema.Resource {
 				ValidateFunc:     azure.ValidateResourceID,
 			},
 
+			"max_shares": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(2),
+			},
+
+			"on_demand_bursting_enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+
 			"encryption_settings": encryptionSettingsSchema(),
 
 			"tags": tags.Schema(),
@@ -200,24 +212,51 @@ func resourceArmManagedDiskCreateUpdate(d *schema.ResourceData, meta interface{}
 		},
 	}
 
+	diskSizeGB := 0
 	if v := d.Get("disk_size_gb"); v != 0 {
-		diskSize := int32(v.(int))
-		props.DiskSizeGB = &diskSize
+		diskSizeGB = v.(int)
+		size := int32(diskSizeGB)
+		props.DiskSizeGB = &size
+	}
+
+	maxShares := d.Get("max_shares").(int)
+	burstingEnabled := d.Get("on_demand_bursting_enabled").(bool)
+	if err := validateManagedDiskMaxSharesAndBursting(storageAccountType, diskSizeGB, maxShares, burstingEnabled); err != nil {
+		return err
+	}
+
+	if maxShares > 0 {
+		shares := int32(maxShares)
+		props.MaxShares = &shares
+	}
+
+	if d.HasChange("on_demand_bursting_enabled") {
+		props.BurstingEnabled = &burstingEnabled
 	}
 
 	// TODO: make this case-sensitive in 2.0
 	if strings.EqualFold(storageAccountType, string(compute.UltraSSDLRS)) {
-		if d.HasChange("disk_iops_read_write") {
-			v := d.Get("disk_iops_read_write")
-			diskIOPS := int64(v.(int))
-			props.DiskIOPSReadWrite = &diskIOPS
+		// mirror the Kubernetes Azure disk controller's defaults (external doc 4) so that
+		// omitting these on an Ultra disk doesn't leave the API to error at apply time.
+		iops := defaultDiskIOPSReadWrite
+		if v, ok := d.GetOk("disk_iops_read_write"); ok {
+			iops = v.(int)
 		}
 
-		if d.HasChange("disk_mbps_read_write") {
-			v := d.Get("disk_mbps_read_write")
-			diskMBps := int32(v.(int))
-			props.DiskMBpsReadWrite = &diskMBps
+		mbps := defaultDiskMBpsReadWrite
+		if v, ok := d.GetOk("disk_mbps_read_write"); ok {
+			mbps = v.(int)
 		}
+
+		if err := validateUltraSSDDiskIOPSAndMBps(diskSizeGB, iops, mbps); err != nil {
+			return err
+		}
+
+		diskIOPS := int64(iops)
+		props.DiskIOPSReadWrite = &diskIOPS
+
+		diskMBps := int32(mbps)
+		props.DiskMBpsReadWrite = &diskMBps
 	} else {
 		if d.HasChange("disk_iops_read_write") || d.HasChange("disk_mbps_read_write") {
 			return fmt.Errorf("[ERROR] disk_iops_read_write and disk_mbps_read_write are only available for UltraSSD disks")
@@ -303,6 +342,247 @@ func resourceArmManagedDiskCreateUpdate(d *schema.ResourceData, meta interface{}
 	return resourceArmManagedDiskRead(d, meta)
 }
 
+// storageAccountTypeConversions are the SKU transitions the Disks API will perform in place - any
+// other combination (e.g. involving Ultra or the ZRS SKUs) requires replacing the disk instead.
+var storageAccountTypeConversions = map[string][]string{
+	string(compute.StandardLRS):    {string(compute.StandardSSDLRS), string(compute.PremiumLRS)},
+	string(compute.StandardSSDLRS): {string(compute.StandardLRS), string(compute.PremiumLRS)},
+	string(compute.PremiumLRS):     {string(compute.StandardLRS), string(compute.StandardSSDLRS)},
+}
+
+func resourceArmManagedDiskUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*clients.Client).Compute.DisksClient
+	vmClient := meta.(*clients.Client).Compute.VMClient
+	ctx, cancel := timeouts.ForUpdate(meta.(*clients.Client).StopContext, d)
+	defer cancel()
+
+	id, err := azure.ParseAzureResourceID(d.Id())
+	if err != nil {
+		return err
+	}
+	resGroup := id.ResourceGroup
+	name := id.Path["disks"]
+
+	disk, err := client.Get(ctx, resGroup, name)
+	if err != nil {
+		return fmt.Errorf("Error retrieving Managed Disk %q (Resource Group %q): %+v", name, resGroup, err)
+	}
+
+	update := compute.DiskUpdate{
+		DiskUpdateProperties: &compute.DiskUpdateProperties{},
+	}
+
+	if d.HasChange("storage_account_type") {
+		oldRaw, newRaw := d.GetChange("storage_account_type")
+		oldType, newType := oldRaw.(string), newRaw.(string)
+
+		if !diskStorageAccountTypeConversionSupported(oldType, newType) {
+			return fmt.Errorf("`storage_account_type` cannot be changed from %q to %q - this disk must be replaced instead", oldType, newType)
+		}
+
+		update.DiskUpdateProperties.Sku = &compute.DiskSku{
+			Name: compute.DiskStorageAccountTypes(newType),
+		}
+	}
+
+	if d.HasChange("disk_size_gb") {
+		oldRaw, newRaw := d.GetChange("disk_size_gb")
+		oldSize, newSize := oldRaw.(int), newRaw.(int)
+
+		if newSize < oldSize {
+			return fmt.Errorf("`disk_size_gb` cannot be shrunk from %d to %d - Managed Disks can only be expanded", oldSize, newSize)
+		}
+
+		if newSize > oldSize {
+			if disk.ManagedBy != nil {
+				online, err := diskAttachedVMSupportsOnlineResize(ctx, vmClient, *disk.ManagedBy)
+				if err != nil {
+					return fmt.Errorf("determining whether Managed Disk %q (Resource Group %q) supports online resize: %+v", name, resGroup, err)
+				}
+
+				if !online {
+					log.Printf("[DEBUG] Virtual Machine %q isn't running - expanding Managed Disk %q (Resource Group %q) offline", *disk.ManagedBy, name, resGroup)
+				}
+			}
+
+			diskSize := int32(newSize)
+			update.DiskUpdateProperties.DiskSizeGB = &diskSize
+		}
+	}
+
+	storageAccountType := d.Get("storage_account_type").(string)
+
+	if d.HasChange("max_shares") {
+		maxShares := d.Get("max_shares").(int)
+		diskSizeGB := d.Get("disk_size_gb").(int)
+		burstingEnabled := d.Get("on_demand_bursting_enabled").(bool)
+
+		if err := validateManagedDiskMaxSharesAndBursting(storageAccountType, diskSizeGB, maxShares, burstingEnabled); err != nil {
+			return err
+		}
+
+		shares := int32(maxShares)
+		update.DiskUpdateProperties.MaxShares = &shares
+	}
+
+	if d.HasChange("disk_iops_read_write") || d.HasChange("disk_mbps_read_write") {
+		if !strings.EqualFold(storageAccountType, string(compute.UltraSSDLRS)) {
+			return fmt.Errorf("[ERROR] disk_iops_read_write and disk_mbps_read_write are only available for UltraSSD disks")
+		}
+
+		diskSizeGB := d.Get("disk_size_gb").(int)
+		iops := d.Get("disk_iops_read_write").(int)
+		mbps := d.Get("disk_mbps_read_write").(int)
+
+		if err := validateUltraSSDDiskIOPSAndMBps(diskSizeGB, iops, mbps); err != nil {
+			return err
+		}
+
+		diskIOPS := int64(iops)
+		update.DiskUpdateProperties.DiskIOPSReadWrite = &diskIOPS
+
+		diskMBps := int32(mbps)
+		update.DiskUpdateProperties.DiskMBpsReadWrite = &diskMBps
+	}
+
+	if d.HasChange("on_demand_bursting_enabled") {
+		burstingEnabled := d.Get("on_demand_bursting_enabled").(bool)
+		if burstingEnabled && !strings.EqualFold(storageAccountType, string(compute.PremiumLRS)) {
+			return fmt.Errorf("`on_demand_bursting_enabled` is only supported for `Premium_LRS` disks")
+		}
+
+		update.DiskUpdateProperties.BurstingEnabled = &burstingEnabled
+	}
+
+	if d.HasChange("encryption_settings") {
+		settings := &compute.EncryptionSettingsCollection{Enabled: utils.Bool(false)}
+		if v, ok := d.GetOk("encryption_settings"); ok {
+			encryptionSettings := v.([]interface{})
+			settings = expandManagedDiskEncryptionSettings(encryptionSettings[0].(map[string]interface{}))
+		}
+		update.DiskUpdateProperties.EncryptionSettingsCollection = settings
+	}
+
+	if d.HasChange("tags") {
+		t := d.Get("tags").(map[string]interface{})
+		update.Tags = tags.Expand(t)
+	}
+
+	future, err := client.Update(ctx, resGroup, name, update)
+	if err != nil {
+		return fmt.Errorf("Error updating Managed Disk %q (Resource Group %q): %+v", name, resGroup, err)
+	}
+
+	if err = future.WaitForCompletionRef(ctx, client.Client); err != nil {
+		return fmt.Errorf("Error waiting for update of Managed Disk %q (Resource Group %q): %+v", name, resGroup, err)
+	}
+
+	return resourceArmManagedDiskRead(d, meta)
+}
+
+// diskStorageAccountTypeConversionSupported mirrors the conversions the Disks API accepts as an
+// in-place SKU change (see the Azure disk controller's conversion matrix, external doc 4) -
+// Standard_LRS, StandardSSD_LRS and Premium_LRS can be freely converted between each other.
+func diskStorageAccountTypeConversionSupported(oldType, newType string) bool {
+	for _, allowed := range storageAccountTypeConversions[oldType] {
+		if strings.EqualFold(allowed, newType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// diskAttachedVMSupportsOnlineResize checks the power state of the Virtual Machine a disk is
+// attached to, returning true only when the VM is running. The VM's size is not checked.
+func diskAttachedVMSupportsOnlineResize(ctx context.Context, client *compute.VirtualMachinesClient, managedByID string) (bool, error) {
+	id, err := azure.ParseAzureResourceID(managedByID)
+	if err != nil {
+		return false, err
+	}
+
+	vm, err := client.Get(ctx, id.ResourceGroup, id.Path["virtualMachines"], compute.InstanceView)
+	if err != nil {
+		return false, fmt.Errorf("retrieving Virtual Machine %q (Resource Group %q): %+v", id.Path["virtualMachines"], id.ResourceGroup, err)
+	}
+
+	if vm.VirtualMachineProperties == nil || vm.VirtualMachineProperties.InstanceView == nil || vm.VirtualMachineProperties.InstanceView.Statuses == nil {
+		return false, nil
+	}
+
+	for _, status := range *vm.VirtualMachineProperties.InstanceView.Statuses {
+		if status.Code != nil && *status.Code == "PowerState/running" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// defaultDiskIOPSReadWrite and defaultDiskMBpsReadWrite mirror the values the Kubernetes Azure
+// managed-disk controller falls back to for Ultra disks (external doc 4) when the caller doesn't
+// specify a throughput tier explicitly.
+const (
+	defaultDiskIOPSReadWrite = 500
+	defaultDiskMBpsReadWrite = 100
+
+	maxUltraSSDIOPSPerGiB = 300
+	maxUltraSSDIOPS       = 160000
+	maxUltraSSDMBpsPerGiB = 256
+	maxUltraSSDMBps       = 2000
+)
+
+// validateUltraSSDDiskIOPSAndMBps enforces the documented per-GiB caps for Ultra SSD throughput -
+// the API accepts out-of-range combinations at create time but then fails asynchronously, so this
+// surfaces the same constraint as a diff-time error instead.
+func validateUltraSSDDiskIOPSAndMBps(diskSizeGB, iops, mbps int) error {
+	if diskSizeGB <= 0 {
+		return nil
+	}
+
+	if iopsCap := diskSizeGB * maxUltraSSDIOPSPerGiB; iops > iopsCap || iops > maxUltraSSDIOPS {
+		return fmt.Errorf("`disk_iops_read_write` of %d exceeds the maximum of %d IOPS for a %d GiB Ultra SSD disk", iops, minInt(iopsCap, maxUltraSSDIOPS), diskSizeGB)
+	}
+
+	if mbpsCap := diskSizeGB * maxUltraSSDMBpsPerGiB; mbps > mbpsCap || mbps > maxUltraSSDMBps {
+		return fmt.Errorf("`disk_mbps_read_write` of %d exceeds the maximum of %d MBps for a %d GiB Ultra SSD disk", mbps, minInt(mbpsCap, maxUltraSSDMBps), diskSizeGB)
+	}
+
+	return nil
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// minDiskSizeGBForSharedDisk is the smallest disk (in GiB) that Azure will allow to carry
+// `max_shares` on a Premium SKU - this corresponds to a P15, the first Premium tier large enough
+// to support clustered-workload shared disks.
+const minDiskSizeGBForSharedDisk = 256
+
+func validateManagedDiskMaxSharesAndBursting(storageAccountType string, diskSizeGB, maxShares int, burstingEnabled bool) error {
+	isPremiumOrUltra := strings.EqualFold(storageAccountType, string(compute.PremiumLRS)) || strings.EqualFold(storageAccountType, string(compute.UltraSSDLRS))
+
+	if maxShares > 0 {
+		if !isPremiumOrUltra {
+			return fmt.Errorf("`max_shares` is only supported for `Premium_LRS` and `UltraSSD_LRS` disks")
+		}
+
+		if strings.EqualFold(storageAccountType, string(compute.PremiumLRS)) && diskSizeGB != 0 && diskSizeGB < minDiskSizeGBForSharedDisk {
+			return fmt.Errorf("`max_shares` requires a `disk_size_gb` of at least %d on `Premium_LRS` disks (a P15 or larger)", minDiskSizeGBForSharedDisk)
+		}
+	}
+
+	if burstingEnabled && !strings.EqualFold(storageAccountType, string(compute.PremiumLRS)) {
+		return fmt.Errorf("`on_demand_bursting_enabled` is only supported for `Premium_LRS` disks")
+	}
+
+	return nil
+}
+
 func resourceArmManagedDiskRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*clients.Client).Compute.DisksClient
 	ctx, cancel := timeouts.ForRead(meta.(*clients.Client).StopContext, d)
@@ -356,6 +636,13 @@ func resourceArmManagedDiskRead(d *schema.ResourceData, meta interface{}) error
 		d.Set("disk_iops_read_write", props.DiskIOPSReadWrite)
 		d.Set("disk_mbps_read_write", props.DiskMBpsReadWrite)
 		d.Set("os_type", props.OsType)
+		d.Set("max_shares", props.MaxShares)
+
+		burstingEnabled := false
+		if props.BurstingEnabled != nil {
+			burstingEnabled = *props.BurstingEnabled
+		}
+		d.Set("on_demand_bursting_enabled", burstingEnabled)
 
 		diskEncryptionSetId := ""
 		if props.Encryption != nil && props.Encryption.DiskEncryptionSetID != nil {
@@ -0,0 +1,392 @@
+package compute
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/helpers/azure"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/clients"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/locks"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/timeouts"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
+)
+
+// virtualMachineResourceName is the lock key all VM-mutating resources in this package share, so
+// that e.g. a `azurerm_managed_disk_attachment` update and a concurrent `azurerm_virtual_machine`
+// change to the same VM serialize against each other instead of racing on StorageProfile.
+const virtualMachineResourceName = "azurerm_virtual_machine"
+
+func resourceArmManagedDiskAttachment() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceArmManagedDiskAttachmentCreate,
+		Read:   resourceArmManagedDiskAttachmentRead,
+		Update: resourceArmManagedDiskAttachmentUpdate,
+		Delete: resourceArmManagedDiskAttachmentDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"managed_disk_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: azure.ValidateResourceID,
+			},
+
+			"virtual_machine_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: azure.ValidateResourceID,
+			},
+
+			"lun": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+
+			// updatable in place - resourceArmManagedDiskAttachmentUpdate changes the matching
+			// DataDisk entry on the VM rather than forcing a detach/re-attach.
+			"caching": {
+				Type:     schema.TypeString,
+				Required: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					string(compute.CachingTypesNone),
+					string(compute.CachingTypesReadOnly),
+					string(compute.CachingTypesReadWrite),
+				}, false),
+			},
+
+			"write_accelerator_enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+func resourceArmManagedDiskAttachmentCreate(d *schema.ResourceData, meta interface{}) error {
+	vmClient := meta.(*clients.Client).Compute.VMClient
+	disksClient := meta.(*clients.Client).Compute.DisksClient
+	ctx, cancel := timeouts.ForCreate(meta.(*clients.Client).StopContext, d)
+	defer cancel()
+
+	log.Printf("[INFO] preparing arguments for Azure ARM Managed Disk Attachment creation.")
+
+	managedDiskId := d.Get("managed_disk_id").(string)
+	virtualMachineId := d.Get("virtual_machine_id").(string)
+	lun := int32(d.Get("lun").(int))
+	caching := d.Get("caching").(string)
+	writeAcceleratorEnabled := d.Get("write_accelerator_enabled").(bool)
+
+	vmId, err := azure.ParseAzureResourceID(virtualMachineId)
+	if err != nil {
+		return err
+	}
+	vmResourceGroup := vmId.ResourceGroup
+	vmName := vmId.Path["virtualMachines"]
+
+	diskId, err := azure.ParseAzureResourceID(managedDiskId)
+	if err != nil {
+		return err
+	}
+	diskName := diskId.Path["disks"]
+
+	disk, err := disksClient.Get(ctx, diskId.ResourceGroup, diskName)
+	if err != nil {
+		return fmt.Errorf("Error retrieving Managed Disk %q (Resource Group %q): %+v", diskName, diskId.ResourceGroup, err)
+	}
+
+	maxShares := int32(0)
+	if disk.DiskProperties != nil && disk.DiskProperties.MaxShares != nil {
+		maxShares = *disk.DiskProperties.MaxShares
+	}
+
+	locks.ByName(vmName, virtualMachineResourceName)
+	defer locks.UnlockByName(vmName, virtualMachineResourceName)
+
+	vm, err := vmClient.Get(ctx, vmResourceGroup, vmName, "")
+	if err != nil {
+		return fmt.Errorf("Error retrieving Virtual Machine %q (Resource Group %q): %+v", vmName, vmResourceGroup, err)
+	}
+
+	if vm.VirtualMachineProperties == nil || vm.VirtualMachineProperties.StorageProfile == nil {
+		return fmt.Errorf("Error retrieving Virtual Machine %q (Resource Group %q): `storage_profile` was nil", vmName, vmResourceGroup)
+	}
+
+	dataDisks := make([]compute.DataDisk, 0)
+	if existing := vm.VirtualMachineProperties.StorageProfile.DataDisks; existing != nil {
+		dataDisks = *existing
+	}
+
+	// dedupe by (VM, LUN) rather than disk ID - a shared disk is expected to be attached to
+	// several VMs at once via separate `azurerm_managed_disk_attachment` resources.
+	for _, existingDisk := range dataDisks {
+		if existingDisk.Lun == nil || *existingDisk.Lun != lun {
+			continue
+		}
+
+		attachedToThisDisk := existingDisk.ManagedDisk != nil && existingDisk.ManagedDisk.ID != nil && strings.EqualFold(*existingDisk.ManagedDisk.ID, managedDiskId)
+		if !attachedToThisDisk {
+			return fmt.Errorf("LUN %d on Virtual Machine %q (Resource Group %q) is already in use by a different Managed Disk", lun, vmName, vmResourceGroup)
+		}
+
+		if maxShares < 2 {
+			return fmt.Errorf("A Data Disk is already attached to Virtual Machine %q (Resource Group %q) at LUN %d", vmName, vmResourceGroup, lun)
+		}
+
+		// the shared disk is already attached at this (VM, LUN) pair - nothing to change on the
+		// VM, but this resource still needs its ID set so it's tracked in state.
+		d.SetId(fmt.Sprintf("%s/dataDisks/%d", virtualMachineId, lun))
+		return resourceArmManagedDiskAttachmentRead(d, meta)
+	}
+
+	dataDisks = append(dataDisks, compute.DataDisk{
+		Lun:                     utils.Int32(lun),
+		CreateOption:            compute.DiskCreateOptionTypesAttach,
+		Caching:                 compute.CachingTypes(caching),
+		WriteAcceleratorEnabled: utils.Bool(writeAcceleratorEnabled),
+		ManagedDisk: &compute.ManagedDiskParameters{
+			ID: utils.String(managedDiskId),
+		},
+	})
+
+	vm.VirtualMachineProperties.StorageProfile.DataDisks = &dataDisks
+
+	// the VM's `resources` aren't relevant to attaching a disk, and the Update API rejects the
+	// payload if they're echoed back, so this only needs the StorageProfile mutation above.
+	vm.Resources = nil
+
+	future, err := vmClient.CreateOrUpdate(ctx, vmResourceGroup, vmName, vm)
+	if err != nil {
+		return fmt.Errorf("Error updating Virtual Machine %q (Resource Group %q) to attach Managed Disk %q: %+v", vmName, vmResourceGroup, diskName, err)
+	}
+
+	if err = future.WaitForCompletionRef(ctx, vmClient.Client); err != nil {
+		return fmt.Errorf("Error waiting for update of Virtual Machine %q (Resource Group %q): %+v", vmName, vmResourceGroup, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/dataDisks/%d", virtualMachineId, lun))
+
+	return resourceArmManagedDiskAttachmentRead(d, meta)
+}
+
+func resourceArmManagedDiskAttachmentUpdate(d *schema.ResourceData, meta interface{}) error {
+	vmClient := meta.(*clients.Client).Compute.VMClient
+	ctx, cancel := timeouts.ForUpdate(meta.(*clients.Client).StopContext, d)
+	defer cancel()
+
+	virtualMachineId, lun, err := parseManagedDiskAttachmentID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	managedDiskId := d.Get("managed_disk_id").(string)
+	caching := d.Get("caching").(string)
+	writeAcceleratorEnabled := d.Get("write_accelerator_enabled").(bool)
+
+	vmId, err := azure.ParseAzureResourceID(virtualMachineId)
+	if err != nil {
+		return err
+	}
+	vmResourceGroup := vmId.ResourceGroup
+	vmName := vmId.Path["virtualMachines"]
+
+	locks.ByName(vmName, virtualMachineResourceName)
+	defer locks.UnlockByName(vmName, virtualMachineResourceName)
+
+	vm, err := vmClient.Get(ctx, vmResourceGroup, vmName, "")
+	if err != nil {
+		return fmt.Errorf("Error retrieving Virtual Machine %q (Resource Group %q): %+v", vmName, vmResourceGroup, err)
+	}
+
+	if vm.VirtualMachineProperties == nil || vm.VirtualMachineProperties.StorageProfile == nil || vm.VirtualMachineProperties.StorageProfile.DataDisks == nil {
+		return fmt.Errorf("Error retrieving Virtual Machine %q (Resource Group %q): `storage_profile` had no Data Disks", vmName, vmResourceGroup)
+	}
+
+	dataDisks := *vm.VirtualMachineProperties.StorageProfile.DataDisks
+	found := false
+	for i := range dataDisks {
+		if dataDisks[i].Lun == nil || *dataDisks[i].Lun != lun {
+			continue
+		}
+
+		if dataDisks[i].ManagedDisk == nil || dataDisks[i].ManagedDisk.ID == nil || !strings.EqualFold(*dataDisks[i].ManagedDisk.ID, managedDiskId) {
+			return fmt.Errorf("LUN %d on Virtual Machine %q (Resource Group %q) is attached to a different Managed Disk than %q", lun, vmName, vmResourceGroup, managedDiskId)
+		}
+
+		dataDisks[i].Caching = compute.CachingTypes(caching)
+		dataDisks[i].WriteAcceleratorEnabled = utils.Bool(writeAcceleratorEnabled)
+		found = true
+		break
+	}
+
+	if !found {
+		return fmt.Errorf("Data Disk Attachment for Managed Disk %q was not found on Virtual Machine %q (Resource Group %q)", managedDiskId, vmName, vmResourceGroup)
+	}
+
+	vm.VirtualMachineProperties.StorageProfile.DataDisks = &dataDisks
+
+	future, err := vmClient.CreateOrUpdate(ctx, vmResourceGroup, vmName, vm)
+	if err != nil {
+		return fmt.Errorf("Error updating Virtual Machine %q (Resource Group %q) to update Disk Attachment at LUN %d: %+v", vmName, vmResourceGroup, lun, err)
+	}
+
+	if err = future.WaitForCompletionRef(ctx, vmClient.Client); err != nil {
+		return fmt.Errorf("Error waiting for update of Virtual Machine %q (Resource Group %q): %+v", vmName, vmResourceGroup, err)
+	}
+
+	return resourceArmManagedDiskAttachmentRead(d, meta)
+}
+
+func resourceArmManagedDiskAttachmentRead(d *schema.ResourceData, meta interface{}) error {
+	vmClient := meta.(*clients.Client).Compute.VMClient
+	ctx, cancel := timeouts.ForRead(meta.(*clients.Client).StopContext, d)
+	defer cancel()
+
+	virtualMachineId, lun, err := parseManagedDiskAttachmentID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	vmId, err := azure.ParseAzureResourceID(virtualMachineId)
+	if err != nil {
+		return err
+	}
+
+	vm, err := vmClient.Get(ctx, vmId.ResourceGroup, vmId.Path["virtualMachines"], "")
+	if err != nil {
+		if utils.ResponseWasNotFound(vm.Response) {
+			log.Printf("[INFO] Virtual Machine %q does not exist - removing Disk Attachment from state", vmId.Path["virtualMachines"])
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error retrieving Virtual Machine %q (Resource Group %q): %+v", vmId.Path["virtualMachines"], vmId.ResourceGroup, err)
+	}
+
+	// a disk attachment has no API surface of its own - its state is reconstructed by scanning
+	// the owning Virtual Machine's data disks for the (VM, LUN) pair this resource manages.
+	var found *compute.DataDisk
+	if vm.VirtualMachineProperties != nil && vm.VirtualMachineProperties.StorageProfile != nil && vm.VirtualMachineProperties.StorageProfile.DataDisks != nil {
+		for _, dataDisk := range *vm.VirtualMachineProperties.StorageProfile.DataDisks {
+			if dataDisk.Lun != nil && *dataDisk.Lun == lun {
+				dataDisk := dataDisk
+				found = &dataDisk
+				break
+			}
+		}
+	}
+
+	if found == nil {
+		log.Printf("[INFO] Data Disk at LUN %d was not found on Virtual Machine %q - removing Disk Attachment from state", lun, vmId.Path["virtualMachines"])
+		d.SetId("")
+		return nil
+	}
+
+	if found.ManagedDisk != nil && found.ManagedDisk.ID != nil {
+		d.Set("managed_disk_id", found.ManagedDisk.ID)
+	}
+	d.Set("virtual_machine_id", virtualMachineId)
+	d.Set("lun", lun)
+	d.Set("caching", string(found.Caching))
+
+	writeAcceleratorEnabled := false
+	if found.WriteAcceleratorEnabled != nil {
+		writeAcceleratorEnabled = *found.WriteAcceleratorEnabled
+	}
+	d.Set("write_accelerator_enabled", writeAcceleratorEnabled)
+
+	return nil
+}
+
+func resourceArmManagedDiskAttachmentDelete(d *schema.ResourceData, meta interface{}) error {
+	vmClient := meta.(*clients.Client).Compute.VMClient
+	ctx, cancel := timeouts.ForDelete(meta.(*clients.Client).StopContext, d)
+	defer cancel()
+
+	virtualMachineId, lun, err := parseManagedDiskAttachmentID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	vmId, err := azure.ParseAzureResourceID(virtualMachineId)
+	if err != nil {
+		return err
+	}
+	vmResourceGroup := vmId.ResourceGroup
+	vmName := vmId.Path["virtualMachines"]
+
+	locks.ByName(vmName, virtualMachineResourceName)
+	defer locks.UnlockByName(vmName, virtualMachineResourceName)
+
+	vm, err := vmClient.Get(ctx, vmResourceGroup, vmName, "")
+	if err != nil {
+		if utils.ResponseWasNotFound(vm.Response) {
+			return nil
+		}
+		return fmt.Errorf("Error retrieving Virtual Machine %q (Resource Group %q): %+v", vmName, vmResourceGroup, err)
+	}
+
+	if vm.VirtualMachineProperties == nil || vm.VirtualMachineProperties.StorageProfile == nil || vm.VirtualMachineProperties.StorageProfile.DataDisks == nil {
+		return nil
+	}
+
+	existing := *vm.VirtualMachineProperties.StorageProfile.DataDisks
+	dataDisks := make([]compute.DataDisk, 0, len(existing))
+	for _, dataDisk := range existing {
+		if dataDisk.Lun != nil && *dataDisk.Lun == lun {
+			continue
+		}
+		dataDisks = append(dataDisks, dataDisk)
+	}
+
+	if len(dataDisks) == len(existing) {
+		return nil
+	}
+
+	vm.VirtualMachineProperties.StorageProfile.DataDisks = &dataDisks
+
+	future, err := vmClient.CreateOrUpdate(ctx, vmResourceGroup, vmName, vm)
+	if err != nil {
+		return fmt.Errorf("Error updating Virtual Machine %q (Resource Group %q) to detach Disk Attachment at LUN %d: %+v", vmName, vmResourceGroup, lun, err)
+	}
+
+	if err = future.WaitForCompletionRef(ctx, vmClient.Client); err != nil {
+		return fmt.Errorf("Error waiting for update of Virtual Machine %q (Resource Group %q): %+v", vmName, vmResourceGroup, err)
+	}
+
+	return nil
+}
+
+// parseManagedDiskAttachmentID splits the synthetic ID this resource sets - "<vmId>/dataDisks/<lun>"
+// - back into its Virtual Machine ID and LUN, since the attachment has no ID of its own to Get by.
+func parseManagedDiskAttachmentID(id string) (string, int32, error) {
+	segments := strings.Split(id, "/dataDisks/")
+	if len(segments) != 2 {
+		return "", 0, fmt.Errorf("Managed Disk Attachment ID %q is not in the expected format `<virtual machine id>/dataDisks/<lun>`", id)
+	}
+
+	lun, err := strconv.Atoi(segments[1])
+	if err != nil {
+		return "", 0, fmt.Errorf("parsing LUN from Managed Disk Attachment ID %q: %+v", id, err)
+	}
+
+	return segments[0], int32(lun), nil
+}
@@ -0,0 +1,300 @@
+package compute
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
+	"github.com/hashicorp/go-azure-helpers/response"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/helpers/azure"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/helpers/tf"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/clients"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/features"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/tags"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/timeouts"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
+)
+
+func resourceArmManagedDiskSnapshot() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceArmManagedDiskSnapshotCreateUpdate,
+		Read:   resourceArmManagedDiskSnapshotRead,
+		Update: resourceArmManagedDiskSnapshotCreateUpdate,
+		Delete: resourceArmManagedDiskSnapshotDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"location": azure.SchemaLocation(),
+
+			"resource_group_name": azure.SchemaResourceGroupName(),
+
+			"create_option": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					string(compute.Copy),
+					string(compute.Import),
+				}, false),
+			},
+
+			"source_uri": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"source_resource_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			// incremental snapshots only store the delta against the previous snapshot in the
+			// same chain, making repeated Copy/Restore cycles far cheaper than full snapshots.
+			"incremental": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"disk_size_gb": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateDiskSizeGB,
+			},
+
+			"disk_encryption_set_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: azure.ValidateResourceID,
+			},
+
+			"encryption_settings": encryptionSettingsSchema(),
+
+			"source_unique_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"tags": tags.Schema(),
+		},
+	}
+}
+
+func resourceArmManagedDiskSnapshotCreateUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*clients.Client).Compute.SnapshotsClient
+	ctx, cancel := timeouts.ForCreateUpdate(meta.(*clients.Client).StopContext, d)
+	defer cancel()
+
+	log.Printf("[INFO] preparing arguments for Azure ARM Managed Disk Snapshot creation.")
+
+	name := d.Get("name").(string)
+	resGroup := d.Get("resource_group_name").(string)
+
+	if features.ShouldResourcesBeImported() && d.IsNewResource() {
+		existing, err := client.Get(ctx, resGroup, name)
+		if err != nil {
+			if !utils.ResponseWasNotFound(existing.Response) {
+				return fmt.Errorf("Error checking for presence of existing Managed Disk Snapshot %q (Resource Group %q): %s", name, resGroup, err)
+			}
+		}
+
+		if existing.ID != nil && *existing.ID != "" {
+			return tf.ImportAsExistsError("azurerm_managed_disk_snapshot", *existing.ID)
+		}
+	}
+
+	location := azure.NormalizeLocation(d.Get("location").(string))
+	createOption := compute.DiskCreateOption(d.Get("create_option").(string))
+	incremental := d.Get("incremental").(bool)
+	t := d.Get("tags").(map[string]interface{})
+
+	props := &compute.SnapshotProperties{
+		CreationData: &compute.CreationData{
+			CreateOption: createOption,
+		},
+		Incremental: utils.Bool(incremental),
+	}
+
+	if v := d.Get("disk_size_gb"); v != 0 {
+		diskSize := int32(v.(int))
+		props.DiskSizeGB = &diskSize
+	}
+
+	if createOption == compute.Import {
+		sourceUri := d.Get("source_uri").(string)
+		if sourceUri == "" {
+			return fmt.Errorf("`source_uri` must be specified when `create_option` is set to `Import`")
+		}
+
+		props.CreationData.SourceURI = utils.String(sourceUri)
+	}
+	if createOption == compute.Copy {
+		sourceResourceId := d.Get("source_resource_id").(string)
+		if sourceResourceId == "" {
+			return fmt.Errorf("`source_resource_id` must be specified when `create_option` is set to `Copy`")
+		}
+
+		props.CreationData.SourceResourceID = utils.String(sourceResourceId)
+	}
+
+	if v, ok := d.GetOk("encryption_settings"); ok {
+		encryptionSettings := v.([]interface{})
+		settings := encryptionSettings[0].(map[string]interface{})
+		props.EncryptionSettingsCollection = expandManagedDiskEncryptionSettings(settings)
+	}
+
+	if diskEncryptionSetId := d.Get("disk_encryption_set_id").(string); diskEncryptionSetId != "" {
+		props.Encryption = &compute.Encryption{
+			Type:                compute.EncryptionAtRestWithCustomerKey,
+			DiskEncryptionSetID: utils.String(diskEncryptionSetId),
+		}
+	}
+
+	snapshot := compute.Snapshot{
+		Name:               &name,
+		Location:           &location,
+		SnapshotProperties: props,
+		Tags:               tags.Expand(t),
+	}
+
+	future, err := client.CreateOrUpdate(ctx, resGroup, name, snapshot)
+	if err != nil {
+		return fmt.Errorf("Error creating/updating Managed Disk Snapshot %q (Resource Group %q): %+v", name, resGroup, err)
+	}
+
+	if err = future.WaitForCompletionRef(ctx, client.Client); err != nil {
+		return fmt.Errorf("Error waiting for create/update of Managed Disk Snapshot %q (Resource Group %q): %+v", name, resGroup, err)
+	}
+
+	read, err := client.Get(ctx, resGroup, name)
+	if err != nil {
+		return fmt.Errorf("Error retrieving Managed Disk Snapshot %q (Resource Group %q): %+v", name, resGroup, err)
+	}
+	if read.ID == nil {
+		return fmt.Errorf("Error reading Managed Disk Snapshot %s (Resource Group %q): ID was nil", name, resGroup)
+	}
+
+	d.SetId(*read.ID)
+
+	return resourceArmManagedDiskSnapshotRead(d, meta)
+}
+
+func resourceArmManagedDiskSnapshotRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*clients.Client).Compute.SnapshotsClient
+	ctx, cancel := timeouts.ForRead(meta.(*clients.Client).StopContext, d)
+	defer cancel()
+
+	id, err := azure.ParseAzureResourceID(d.Id())
+	if err != nil {
+		return err
+	}
+	resGroup := id.ResourceGroup
+	name := id.Path["snapshots"]
+
+	resp, err := client.Get(ctx, resGroup, name)
+	if err != nil {
+		if utils.ResponseWasNotFound(resp.Response) {
+			log.Printf("[INFO] Disk Snapshot %q does not exist - removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("[ERROR] Error making Read request on Azure Managed Disk Snapshot %s (resource group %s): %s", name, resGroup, err)
+	}
+
+	d.Set("name", resp.Name)
+	d.Set("resource_group_name", resGroup)
+
+	if location := resp.Location; location != nil {
+		d.Set("location", azure.NormalizeLocation(*location))
+	}
+
+	if err := flattenArmManagedDiskSnapshotProperties(d, resp.SnapshotProperties); err != nil {
+		return err
+	}
+
+	return tags.FlattenAndSet(d, resp.Tags)
+}
+
+func flattenArmManagedDiskSnapshotProperties(d *schema.ResourceData, props *compute.SnapshotProperties) error {
+	if props == nil {
+		return nil
+	}
+
+	if creationData := props.CreationData; creationData != nil {
+		d.Set("create_option", string(creationData.CreateOption))
+		d.Set("source_resource_id", creationData.SourceResourceID)
+		d.Set("source_uri", creationData.SourceURI)
+	}
+
+	d.Set("disk_size_gb", props.DiskSizeGB)
+	d.Set("source_unique_id", props.UniqueID)
+
+	incremental := false
+	if props.Incremental != nil {
+		incremental = *props.Incremental
+	}
+	d.Set("incremental", incremental)
+
+	diskEncryptionSetId := ""
+	if props.Encryption != nil && props.Encryption.DiskEncryptionSetID != nil {
+		diskEncryptionSetId = *props.Encryption.DiskEncryptionSetID
+	}
+	d.Set("disk_encryption_set_id", diskEncryptionSetId)
+
+	if err := d.Set("encryption_settings", flattenManagedDiskEncryptionSettings(props.EncryptionSettingsCollection)); err != nil {
+		return fmt.Errorf("Error setting `encryption_settings`: %+v", err)
+	}
+
+	return nil
+}
+
+func resourceArmManagedDiskSnapshotDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*clients.Client).Compute.SnapshotsClient
+	ctx, cancel := timeouts.ForDelete(meta.(*clients.Client).StopContext, d)
+	defer cancel()
+
+	id, err := azure.ParseAzureResourceID(d.Id())
+	if err != nil {
+		return err
+	}
+	resGroup := id.ResourceGroup
+	name := id.Path["snapshots"]
+
+	future, err := client.Delete(ctx, resGroup, name)
+	if err != nil {
+		if !response.WasNotFound(future.Response()) {
+			return fmt.Errorf("Error deleting Managed Disk Snapshot %q (Resource Group %q): %+v", name, resGroup, err)
+		}
+	}
+
+	if err = future.WaitForCompletionRef(ctx, client.Client); err != nil {
+		if !response.WasNotFound(future.Response()) {
+			return fmt.Errorf("Error waiting for deletion of Managed Disk Snapshot %q (Resource Group %q): %+v", name, resGroup, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package compute
+
+import "testing"
+
+func TestValidateUltraSSDDiskIOPSAndMBps(t *testing.T) {
+	cases := []struct {
+		name        string
+		diskSizeGB  int
+		iops        int
+		mbps        int
+		expectError bool
+	}{
+		{
+			name:        "defaults within the per-GiB caps",
+			diskSizeGB:  100,
+			iops:        defaultDiskIOPSReadWrite,
+			mbps:        defaultDiskMBpsReadWrite,
+			expectError: false,
+		},
+		{
+			name:        "iops exceeds the per-GiB cap",
+			diskSizeGB:  1,
+			iops:        defaultDiskIOPSReadWrite, // 500 > 1*300
+			mbps:        defaultDiskMBpsReadWrite,
+			expectError: true,
+		},
+		{
+			name:        "iops exceeds the absolute cap",
+			diskSizeGB:  100000,
+			iops:        maxUltraSSDIOPS + 1,
+			mbps:        defaultDiskMBpsReadWrite,
+			expectError: true,
+		},
+		{
+			name:        "mbps exceeds the per-GiB cap",
+			diskSizeGB:  1,
+			iops:        maxUltraSSDIOPSPerGiB, // within the 1 GiB IOPS cap
+			mbps:        maxUltraSSDMBpsPerGiB + 1,
+			expectError: true,
+		},
+		{
+			name:        "mbps exceeds the absolute cap",
+			diskSizeGB:  100000,
+			iops:        defaultDiskIOPSReadWrite,
+			mbps:        maxUltraSSDMBps + 1,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateUltraSSDDiskIOPSAndMBps(tc.diskSizeGB, tc.iops, tc.mbps)
+			if tc.expectError && err == nil {
+				t.Fatalf("expected an error but got none")
+			}
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %+v", err)
+			}
+		})
+	}
+}
+
+func TestValidateManagedDiskMaxSharesAndBursting(t *testing.T) {
+	cases := []struct {
+		name               string
+		storageAccountType string
+		diskSizeGB         int
+		maxShares          int
+		burstingEnabled    bool
+		expectError        bool
+	}{
+		{
+			name:               "max_shares on Standard_LRS is rejected",
+			storageAccountType: "Standard_LRS",
+			diskSizeGB:         256,
+			maxShares:          2,
+			expectError:        true,
+		},
+		{
+			name:               "max_shares on Premium_LRS below the 256 GiB floor is rejected",
+			storageAccountType: "Premium_LRS",
+			diskSizeGB:         128,
+			maxShares:          2,
+			expectError:        true,
+		},
+		{
+			name:               "max_shares on a large enough Premium_LRS disk is accepted",
+			storageAccountType: "Premium_LRS",
+			diskSizeGB:         256,
+			maxShares:          2,
+			expectError:        false,
+		},
+		{
+			name:               "bursting on a non-Premium disk is rejected",
+			storageAccountType: "StandardSSD_LRS",
+			diskSizeGB:         128,
+			burstingEnabled:    true,
+			expectError:        true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateManagedDiskMaxSharesAndBursting(tc.storageAccountType, tc.diskSizeGB, tc.maxShares, tc.burstingEnabled)
+			if tc.expectError && err == nil {
+				t.Fatalf("expected an error but got none")
+			}
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %+v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,343 @@
+package compute
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
+	"github.com/hashicorp/go-azure-helpers/response"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/helpers/azure"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/helpers/tf"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/clients"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/features"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/tags"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/timeouts"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
+)
+
+// zoneRedundantStorageAccountTypes are the only SKUs the regional disk API accepts - regular
+// (single-zone or zonal) SKUs must go through `azurerm_managed_disk` instead.
+var zoneRedundantStorageAccountTypes = []string{
+	"StandardSSD_ZRS",
+	"Premium_ZRS",
+}
+
+func resourceArmManagedRegionDisk() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceArmManagedRegionDiskCreateUpdate,
+		Read:   resourceArmManagedRegionDiskRead,
+		Update: resourceArmManagedRegionDiskCreateUpdate,
+		Delete: resourceArmManagedRegionDiskDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"location": azure.SchemaLocation(),
+
+			"resource_group_name": azure.SchemaResourceGroupName(),
+
+			// unlike `azurerm_managed_disk`'s `zones` (a single fault-domain zone the disk is
+			// pinned to), `replica_zones` is the set of zones the disk is synchronously
+			// replicated across and therefore requires at least two entries. Azure doesn't
+			// support changing a disk's replication topology in place, so this is ForceNew -
+			// unlike `resourceArmManagedDisk`'s `storage_account_type`, there's no conversion API
+			// to route this through.
+			"replica_zones": {
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				MinItems: 2,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+			},
+
+			"storage_account_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(zoneRedundantStorageAccountTypes, false),
+			},
+
+			"create_option": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					string(compute.Copy),
+					string(compute.Empty),
+					string(compute.Restore),
+				}, false),
+			},
+
+			"source_resource_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"disk_size_gb": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateDiskSizeGB,
+			},
+
+			"tags": tags.Schema(),
+		},
+	}
+}
+
+func resourceArmManagedRegionDiskCreateUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*clients.Client).Compute.DisksClient
+	ctx, cancel := timeouts.ForCreateUpdate(meta.(*clients.Client).StopContext, d)
+	defer cancel()
+
+	log.Printf("[INFO] preparing arguments for Azure ARM Managed Region Disk creation.")
+
+	name := d.Get("name").(string)
+	resGroup := d.Get("resource_group_name").(string)
+
+	if features.ShouldResourcesBeImported() && d.IsNewResource() {
+		existing, err := client.Get(ctx, resGroup, name)
+		if err != nil {
+			if !utils.ResponseWasNotFound(existing.Response) {
+				return fmt.Errorf("Error checking for presence of existing Managed Region Disk %q (Resource Group %q): %s", name, resGroup, err)
+			}
+		}
+
+		if existing.ID != nil && *existing.ID != "" {
+			return tf.ImportAsExistsError("azurerm_managed_region_disk", *existing.ID)
+		}
+	}
+
+	location := azure.NormalizeLocation(d.Get("location").(string))
+	createOption := compute.DiskCreateOption(d.Get("create_option").(string))
+	storageAccountType := d.Get("storage_account_type").(string)
+	t := d.Get("tags").(map[string]interface{})
+
+	replicaZonesRaw := d.Get("replica_zones").([]interface{})
+	replicaZones := utils.ExpandStringSlice(replicaZonesRaw)
+	if len(*replicaZones) < 2 {
+		return fmt.Errorf("`replica_zones` must contain at least two zones for zone-redundant storage")
+	}
+
+	props := &compute.DiskProperties{
+		CreationData: &compute.CreationData{
+			CreateOption: createOption,
+		},
+		Encryption: &compute.Encryption{
+			Type: compute.EncryptionAtRestWithPlatformKey,
+		},
+	}
+
+	if v := d.Get("disk_size_gb"); v != 0 {
+		diskSize := int32(v.(int))
+		props.DiskSizeGB = &diskSize
+	}
+
+	if createOption == compute.Copy || createOption == compute.Restore {
+		sourceResourceId := d.Get("source_resource_id").(string)
+		if sourceResourceId == "" {
+			return fmt.Errorf("`source_resource_id` must be specified when `create_option` is set to `Copy` or `Restore`")
+		}
+
+		sourceIsCompatible, err := sourceSupportsReplicaZones(ctx, meta.(*clients.Client), sourceResourceId, *replicaZones)
+		if err != nil {
+			return fmt.Errorf("validating `source_resource_id` %q: %+v", sourceResourceId, err)
+		}
+		if !sourceIsCompatible {
+			return fmt.Errorf("`source_resource_id` must be a regional disk or snapshot whose replica zones match `replica_zones`")
+		}
+
+		props.CreationData.SourceResourceID = utils.String(sourceResourceId)
+	}
+
+	createDisk := compute.Disk{
+		Name:           &name,
+		Location:       &location,
+		DiskProperties: props,
+		Sku: &compute.DiskSku{
+			Name: compute.DiskStorageAccountTypes(storageAccountType),
+		},
+		Tags:  tags.Expand(t),
+		Zones: replicaZones,
+	}
+
+	future, err := client.CreateOrUpdate(ctx, resGroup, name, createDisk)
+	if err != nil {
+		return fmt.Errorf("Error creating/updating Managed Region Disk %q (Resource Group %q): %+v", name, resGroup, err)
+	}
+
+	if err = future.WaitForCompletionRef(ctx, client.Client); err != nil {
+		return fmt.Errorf("Error waiting for create/update of Managed Region Disk %q (Resource Group %q): %+v", name, resGroup, err)
+	}
+
+	read, err := client.Get(ctx, resGroup, name)
+	if err != nil {
+		return fmt.Errorf("Error retrieving Managed Region Disk %q (Resource Group %q): %+v", name, resGroup, err)
+	}
+	if read.ID == nil {
+		return fmt.Errorf("Error reading Managed Region Disk %s (Resource Group %q): ID was nil", name, resGroup)
+	}
+
+	d.SetId(*read.ID)
+
+	return resourceArmManagedRegionDiskRead(d, meta)
+}
+
+func resourceArmManagedRegionDiskRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*clients.Client).Compute.DisksClient
+	ctx, cancel := timeouts.ForRead(meta.(*clients.Client).StopContext, d)
+	defer cancel()
+
+	id, err := azure.ParseAzureResourceID(d.Id())
+	if err != nil {
+		return err
+	}
+	resGroup := id.ResourceGroup
+	name := id.Path["disks"]
+
+	resp, err := client.Get(ctx, resGroup, name)
+	if err != nil {
+		if utils.ResponseWasNotFound(resp.Response) {
+			log.Printf("[INFO] Disk %q does not exist - removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("[ERROR] Error making Read request on Azure Managed Region Disk %s (resource group %s): %s", name, resGroup, err)
+	}
+
+	d.Set("name", resp.Name)
+	d.Set("resource_group_name", resGroup)
+	d.Set("replica_zones", utils.FlattenStringSlice(resp.Zones))
+
+	if location := resp.Location; location != nil {
+		d.Set("location", azure.NormalizeLocation(*location))
+	}
+
+	if sku := resp.Sku; sku != nil {
+		d.Set("storage_account_type", string(sku.Name))
+	}
+
+	if props := resp.DiskProperties; props != nil {
+		if creationData := props.CreationData; creationData != nil {
+			d.Set("create_option", string(creationData.CreateOption))
+			d.Set("source_resource_id", creationData.SourceResourceID)
+		}
+
+		d.Set("disk_size_gb", props.DiskSizeGB)
+	}
+
+	return tags.FlattenAndSet(d, resp.Tags)
+}
+
+func resourceArmManagedRegionDiskDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*clients.Client).Compute.DisksClient
+	ctx, cancel := timeouts.ForDelete(meta.(*clients.Client).StopContext, d)
+	defer cancel()
+
+	id, err := azure.ParseAzureResourceID(d.Id())
+	if err != nil {
+		return err
+	}
+	resGroup := id.ResourceGroup
+	name := id.Path["disks"]
+
+	future, err := client.Delete(ctx, resGroup, name)
+	if err != nil {
+		if !response.WasNotFound(future.Response()) {
+			return fmt.Errorf("Error deleting Managed Region Disk %q (Resource Group %q): %+v", name, resGroup, err)
+		}
+	}
+
+	if err = future.WaitForCompletionRef(ctx, client.Client); err != nil {
+		if !response.WasNotFound(future.Response()) {
+			return fmt.Errorf("Error waiting for deletion of Managed Region Disk %q (Resource Group %q): %+v", name, resGroup, err)
+		}
+	}
+
+	return nil
+}
+
+// sourceSupportsReplicaZones confirms that a `Copy`/`Restore` source (another regional disk, or a
+// snapshot taken of one) carries the same set of replica zones as the disk being provisioned -
+// Azure rejects restores that would change the replication topology mid-copy.
+func sourceSupportsReplicaZones(ctx context.Context, client *clients.Client, sourceResourceId string, replicaZones []string) (bool, error) {
+	id, err := azure.ParseAzureResourceID(sourceResourceId)
+	if err != nil {
+		return false, err
+	}
+
+	if name, ok := id.Path["disks"]; ok {
+		disk, err := client.Compute.DisksClient.Get(ctx, id.ResourceGroup, name)
+		if err != nil {
+			if utils.ResponseWasNotFound(disk.Response) {
+				return false, fmt.Errorf("source disk %q (Resource Group %q) was not found", name, id.ResourceGroup)
+			}
+			return false, err
+		}
+
+		return zonesMatch(disk.Zones, replicaZones), nil
+	}
+
+	if name, ok := id.Path["snapshots"]; ok {
+		snapshot, err := client.Compute.SnapshotsClient.Get(ctx, id.ResourceGroup, name)
+		if err != nil {
+			if utils.ResponseWasNotFound(snapshot.Response) {
+				return false, fmt.Errorf("source snapshot %q (Resource Group %q) was not found", name, id.ResourceGroup)
+			}
+			return false, err
+		}
+
+		// a snapshot carries no `zones` of its own in this API version - the only way to verify
+		// it matches `replica_zones` is to trace it back to the (regional) disk it was taken
+		// from and compare that disk's zones instead.
+		creationData := snapshot.SnapshotProperties
+		if creationData == nil || creationData.CreationData == nil || creationData.CreationData.SourceResourceID == nil {
+			return false, fmt.Errorf("source snapshot %q (Resource Group %q) does not reference the source disk it was taken from, so its replica-zone compatibility cannot be verified", name, id.ResourceGroup)
+		}
+
+		return sourceSupportsReplicaZones(ctx, client, *creationData.CreationData.SourceResourceID, replicaZones)
+	}
+
+	return false, fmt.Errorf("`source_resource_id` must reference a `disks` or `snapshots` resource")
+}
+
+func zonesMatch(a *[]string, b []string) bool {
+	if a == nil || len(*a) != len(b) {
+		return false
+	}
+
+	seen := make(map[string]bool, len(*a))
+	for _, z := range *a {
+		seen[z] = true
+	}
+	for _, z := range b {
+		if !seen[z] {
+			return false
+		}
+	}
+
+	return true
+}